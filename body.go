@@ -0,0 +1,228 @@
+package ginlogrus
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"gopkg.in/gin-gonic/gin.v1"
+)
+
+// defaultCaptureBodyLimit is used when LoggerConfig.CaptureBodies is true
+// but CaptureBodyLimit is left at its zero value.
+const defaultCaptureBodyLimit = 4096
+
+// defaultCaptureContentTypes is used when LoggerConfig.CaptureBodies is
+// true but CaptureContentTypes is left empty. The "text/" entry matches
+// every text/* subtype; RedactJSONFields does not apply to those bodies,
+// since free text has no field structure to redact against.
+var defaultCaptureContentTypes = []string{
+	"application/json",
+	"application/x-www-form-urlencoded",
+	"text/",
+}
+
+// cappedBuffer collects up to limit bytes and silently drops the rest,
+// recording that it did so. It always reports a full, error-free write so
+// it can sit behind an io.TeeReader or a ResponseWriter without altering
+// the behavior of the real reader/writer it shadows.
+type cappedBuffer struct {
+	buf       bytes.Buffer
+	limit     int
+	truncated bool
+}
+
+func newCappedBuffer(limit int) *cappedBuffer {
+	if limit <= 0 {
+		limit = defaultCaptureBodyLimit
+	}
+	return &cappedBuffer{limit: limit}
+}
+
+func (c *cappedBuffer) Write(p []byte) (int, error) {
+	remaining := c.limit - c.buf.Len()
+	if remaining <= 0 {
+		if len(p) > 0 {
+			c.truncated = true
+		}
+		return len(p), nil
+	}
+	if len(p) > remaining {
+		c.buf.Write(p[:remaining])
+		c.truncated = true
+		return len(p), nil
+	}
+	c.buf.Write(p)
+	return len(p), nil
+}
+
+// responseBodyWriter wraps gin.ResponseWriter so every write is duplicated
+// into a capped buffer for logging, in addition to reaching the client.
+type responseBodyWriter struct {
+	gin.ResponseWriter
+	body *cappedBuffer
+}
+
+func (w *responseBodyWriter) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *responseBodyWriter) WriteString(s string) (int, error) {
+	w.body.Write([]byte(s))
+	return w.ResponseWriter.WriteString(s)
+}
+
+func contentTypeAllowed(contentType string, allow []string) bool {
+	ct := contentType
+	if i := strings.IndexByte(ct, ';'); i >= 0 {
+		ct = ct[:i]
+	}
+	ct = strings.TrimSpace(ct)
+
+	for _, a := range allow {
+		if strings.HasSuffix(a, "/") {
+			if strings.HasPrefix(ct, a) {
+				return true
+			}
+			continue
+		}
+		if ct == a {
+			return true
+		}
+	}
+	return false
+}
+
+func redactedHeaders(h http.Header, redact []string) map[string]string {
+	redactSet := make(map[string]struct{}, len(redact))
+	for _, k := range redact {
+		redactSet[http.CanonicalHeaderKey(k)] = struct{}{}
+	}
+
+	out := make(map[string]string, len(h))
+	for k, v := range h {
+		if _, ok := redactSet[http.CanonicalHeaderKey(k)]; ok {
+			out[k] = "***"
+			continue
+		}
+		out[k] = strings.Join(v, ",")
+	}
+	return out
+}
+
+// redactJSONFields returns raw with any object key listed in fields
+// replaced by "***", or raw unchanged if it isn't a JSON document or
+// fields is empty.
+func redactJSONFields(raw []byte, fields []string) []byte {
+	if len(fields) == 0 || len(raw) == 0 {
+		return raw
+	}
+
+	var data interface{}
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return raw
+	}
+
+	redactSet := make(map[string]struct{}, len(fields))
+	for _, f := range fields {
+		redactSet[f] = struct{}{}
+	}
+	redactJSONValue(data, redactSet)
+
+	out, err := json.Marshal(data)
+	if err != nil {
+		return raw
+	}
+	return out
+}
+
+func redactJSONValue(v interface{}, fields map[string]struct{}) {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		for k, vv := range val {
+			if _, ok := fields[k]; ok {
+				val[k] = "***"
+				continue
+			}
+			redactJSONValue(vv, fields)
+		}
+	case []interface{}:
+		for _, item := range val {
+			redactJSONValue(item, fields)
+		}
+	}
+}
+
+// redactFormFields returns raw with any application/x-www-form-urlencoded
+// value whose key is listed in fields replaced by "***", or raw unchanged
+// if it doesn't parse as a query string or fields is empty.
+func redactFormFields(raw []byte, fields []string) []byte {
+	if len(fields) == 0 || len(raw) == 0 {
+		return raw
+	}
+
+	values, err := url.ParseQuery(string(raw))
+	if err != nil {
+		return raw
+	}
+
+	redactSet := make(map[string]struct{}, len(fields))
+	for _, f := range fields {
+		redactSet[f] = struct{}{}
+	}
+
+	for k := range values {
+		if _, ok := redactSet[k]; !ok {
+			continue
+		}
+		for i := range values[k] {
+			values[k][i] = "***"
+		}
+	}
+
+	return []byte(values.Encode())
+}
+
+// redactBody applies the field redaction appropriate for contentType -
+// JSON keys for application/json, form keys for
+// application/x-www-form-urlencoded - and returns raw unchanged for any
+// other content type, since there's no reliable way to redact by field
+// name in free-form text.
+func redactBody(raw []byte, contentType string, fields []string) []byte {
+	ct := contentType
+	if i := strings.IndexByte(ct, ';'); i >= 0 {
+		ct = ct[:i]
+	}
+	ct = strings.TrimSpace(ct)
+
+	switch ct {
+	case "application/json":
+		return redactJSONFields(raw, fields)
+	case "application/x-www-form-urlencoded":
+		return redactFormFields(raw, fields)
+	default:
+		return raw
+	}
+}
+
+// captureRequestBody tees c.Request.Body into a cappedBuffer and puts the
+// body back so handlers downstream still see the full, unmodified stream.
+// It returns nil if the request has no body or its content type isn't in
+// allow.
+func captureRequestBody(c *gin.Context, limit int, allow []string) *cappedBuffer {
+	if c.Request.Body == nil {
+		return nil
+	}
+	if !contentTypeAllowed(c.Request.Header.Get("Content-Type"), allow) {
+		return nil
+	}
+
+	buf := newCappedBuffer(limit)
+	c.Request.Body = ioutil.NopCloser(io.TeeReader(c.Request.Body, buf))
+	return buf
+}
@@ -0,0 +1,125 @@
+package ginlogrus
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCappedBufferTruncates(t *testing.T) {
+	buf := newCappedBuffer(4)
+
+	n, err := buf.Write([]byte("hello world"))
+	if err != nil {
+		t.Fatalf("Write returned error: %v", err)
+	}
+	if n != len("hello world") {
+		t.Fatalf("Write returned n=%d, want %d (tee readers/writers must see a full write)", n, len("hello world"))
+	}
+	if got := buf.buf.String(); got != "hell" {
+		t.Errorf("buffered content = %q, want %q", got, "hell")
+	}
+	if !buf.truncated {
+		t.Error("truncated = false, want true")
+	}
+}
+
+func TestCappedBufferUnderLimit(t *testing.T) {
+	buf := newCappedBuffer(64)
+
+	if _, err := buf.Write([]byte("short")); err != nil {
+		t.Fatalf("Write returned error: %v", err)
+	}
+	if got := buf.buf.String(); got != "short" {
+		t.Errorf("buffered content = %q, want %q", got, "short")
+	}
+	if buf.truncated {
+		t.Error("truncated = true, want false")
+	}
+}
+
+func TestCappedBufferDefaultLimit(t *testing.T) {
+	buf := newCappedBuffer(0)
+	if buf.limit != defaultCaptureBodyLimit {
+		t.Errorf("limit = %d, want default %d", buf.limit, defaultCaptureBodyLimit)
+	}
+}
+
+func TestContentTypeAllowed(t *testing.T) {
+	allow := []string{"application/json", "application/x-www-form-urlencoded"}
+
+	cases := []struct {
+		contentType string
+		want        bool
+	}{
+		{"application/json", true},
+		{"application/json; charset=utf-8", true},
+		{"application/x-www-form-urlencoded", true},
+		{"text/plain", false},
+		{"", false},
+	}
+	for _, tc := range cases {
+		if got := contentTypeAllowed(tc.contentType, allow); got != tc.want {
+			t.Errorf("contentTypeAllowed(%q) = %v, want %v", tc.contentType, got, tc.want)
+		}
+	}
+}
+
+func TestContentTypeAllowedPrefixMatch(t *testing.T) {
+	allow := []string{"text/"}
+	if !contentTypeAllowed("text/csv", allow) {
+		t.Error("expected text/csv to match text/ prefix")
+	}
+	if contentTypeAllowed("application/json", allow) {
+		t.Error("expected application/json not to match text/ prefix")
+	}
+}
+
+func TestRedactJSONFields(t *testing.T) {
+	raw := []byte(`{"username":"bob","password":"hunter2","nested":{"password":"hunter2"}}`)
+
+	out := redactJSONFields(raw, []string{"password"})
+
+	if strings.Contains(string(out), "hunter2") {
+		t.Errorf("redacted output still contains the secret: %s", out)
+	}
+	if !strings.Contains(string(out), "bob") {
+		t.Errorf("redaction removed an unrelated field: %s", out)
+	}
+}
+
+func TestRedactJSONFieldsNoFields(t *testing.T) {
+	raw := []byte(`{"password":"hunter2"}`)
+	out := redactJSONFields(raw, nil)
+	if string(out) != string(raw) {
+		t.Errorf("got %s, want input unchanged when no fields configured", out)
+	}
+}
+
+func TestRedactJSONFieldsNotJSON(t *testing.T) {
+	raw := []byte("not json at all")
+	out := redactJSONFields(raw, []string{"password"})
+	if string(out) != string(raw) {
+		t.Errorf("got %s, want non-JSON input unchanged", out)
+	}
+}
+
+func TestRedactFormFields(t *testing.T) {
+	raw := []byte("username=bob&password=hunter2")
+
+	out := redactBody(raw, "application/x-www-form-urlencoded", []string{"password"})
+
+	if strings.Contains(string(out), "hunter2") {
+		t.Errorf("redacted form body still contains the secret: %s", out)
+	}
+	if !strings.Contains(string(out), "bob") {
+		t.Errorf("redaction removed an unrelated field: %s", out)
+	}
+}
+
+func TestRedactBodyUnknownContentTypeUnchanged(t *testing.T) {
+	raw := []byte("password=hunter2 plain text")
+	out := redactBody(raw, "text/plain", []string{"password"})
+	if string(out) != string(raw) {
+		t.Errorf("got %s, want text/plain left untouched (no field structure to redact)", out)
+	}
+}
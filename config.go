@@ -0,0 +1,262 @@
+package ginlogrus
+
+import (
+	"io"
+	"net/http"
+	"regexp"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"gopkg.in/gin-gonic/gin.v1"
+)
+
+// LogFormatterParams is the set of request/response fields handed to a
+// custom LoggerConfig.Formatter so it can build the log message string.
+// The structured fields themselves (status, method, path, ...) are always
+// attached to the logrus.Entry regardless of the Formatter in use.
+type LogFormatterParams struct {
+	Request      *http.Request
+	TimeStamp    time.Time
+	StatusCode   int
+	Latency      time.Duration
+	ClientIP     string
+	Method       string
+	Path         string
+	BodySize     int
+	ErrorMessage string
+	// TimeFormat is LoggerConfig.TimeFormat, for a Formatter that renders
+	// TimeStamp.
+	TimeFormat string
+}
+
+// LoggerConfig defines the config for LoggerWithConfig, analogous to gin's
+// own gin.LoggerConfig.
+type LoggerConfig struct {
+	// Logger is the *logrus.Logger instance to log through. When nil, a
+	// new logrus.Logger is created.
+	Logger *logrus.Logger
+
+	// Tag is included as the "tag" field on every entry, defaulting to
+	// "GIN" when empty.
+	Tag string
+
+	// Formatter builds the log message from the request/response fields.
+	// Defaults to the request path followed by any gin errors.
+	Formatter func(LogFormatterParams) string
+
+	// Output, when set, is assigned to Logger.Out when LoggerWithConfig
+	// is called.
+	Output io.Writer
+
+	// AccessOutput, when set, routes 2xx/3xx access lines to this writer
+	// instead of Output/Logger.Out, e.g. a RotatingWriter dedicated to
+	// access logs.
+	AccessOutput io.Writer
+
+	// ErrorOutput, when set, routes 4xx/5xx lines to this writer instead
+	// of Output/Logger.Out, e.g. a separate writer an alerting pipeline
+	// tails.
+	ErrorOutput io.Writer
+
+	// Level, when set, is assigned to Logger.Level when LoggerWithConfig
+	// is called. The zero value (logrus.PanicLevel) is treated as "not
+	// set" and leaves Logger's current level untouched.
+	Level logrus.Level
+
+	// UTC, when true, timestamps log entries in UTC instead of local time.
+	UTC bool
+
+	// TimeFormat is passed through as LogFormatterParams.TimeFormat for
+	// formatting TimeStamp; it has no effect unless Formatter uses it.
+	TimeFormat string
+
+	// SkipPaths lists exact request paths that should not be logged.
+	SkipPaths []string
+
+	// SkipPathRegexps lists additional path patterns that should not be
+	// logged, checked after SkipPaths.
+	SkipPathRegexps []*regexp.Regexp
+
+	// CaptureBodies, when true, attaches the request and response bodies
+	// to the log entry as the "request_body" and "response_body" fields,
+	// for request/response content types in CaptureContentTypes.
+	CaptureBodies bool
+
+	// CaptureBodyLimit caps how many bytes of each body are kept; the
+	// remainder is discarded and "request_body_truncated" /
+	// "response_body_truncated" is set to true. Defaults to 4KB.
+	CaptureBodyLimit int
+
+	// CaptureContentTypes allow-lists which Content-Type a request or
+	// response body must have to be captured. An entry ending in "/"
+	// matches any subtype. Defaults to application/json,
+	// application/x-www-form-urlencoded and text/*.
+	CaptureContentTypes []string
+
+	// RedactHeaders lists request header names (case-insensitive) whose
+	// value is replaced with "***" in the "request_headers" field.
+	RedactHeaders []string
+
+	// RedactJSONFields lists field names whose value is replaced with
+	// "***" before a captured body is logged: JSON object keys for
+	// application/json bodies, and form keys for
+	// application/x-www-form-urlencoded bodies. It has no effect on any
+	// other content type.
+	RedactJSONFields []string
+}
+
+var defaultLogFormatter = func(p LogFormatterParams) string {
+	msg := p.Path
+	if p.ErrorMessage != "" {
+		msg = msg + " " + p.ErrorMessage
+	}
+	return msg
+}
+
+// LoggerWithConfig returns a gin.HandlerFunc using the given LoggerConfig.
+// Unlike Logger, it never touches global logrus state and never reuses a
+// package-level logger or color reset variable, so it is safe to mount
+// several instances - each with its own *logrus.Logger, sink and level -
+// on different router groups at the same time.
+func LoggerWithConfig(cfg LoggerConfig) gin.HandlerFunc {
+	l := cfg.Logger
+	if l == nil {
+		l = logrus.New()
+	}
+
+	if cfg.Output != nil {
+		l.Out = cfg.Output
+	}
+	if cfg.Level != logrus.PanicLevel {
+		l.Level = cfg.Level
+	}
+
+	tag := cfg.Tag
+	if tag == "" {
+		tag = "GIN"
+	}
+
+	formatter := cfg.Formatter
+	if formatter == nil {
+		formatter = defaultLogFormatter
+	}
+
+	skip := make(map[string]struct{}, len(cfg.SkipPaths))
+	for _, p := range cfg.SkipPaths {
+		skip[p] = struct{}{}
+	}
+
+	loggerFor := func(out io.Writer) *logrus.Logger {
+		if out == nil {
+			return l
+		}
+		return &logrus.Logger{
+			Out:       out,
+			Formatter: l.Formatter,
+			Hooks:     l.Hooks,
+			Level:     l.Level,
+		}
+	}
+	accessLogger := loggerFor(cfg.AccessOutput)
+	errorLogger := loggerFor(cfg.ErrorOutput)
+
+	captureContentTypes := cfg.CaptureContentTypes
+	if cfg.CaptureBodies && len(captureContentTypes) == 0 {
+		captureContentTypes = defaultCaptureContentTypes
+	}
+
+	return func(c *gin.Context) {
+		path := c.Request.URL.Path
+
+		if _, ok := skip[path]; ok {
+			c.Next()
+			return
+		}
+		for _, re := range cfg.SkipPathRegexps {
+			if re.MatchString(path) {
+				c.Next()
+				return
+			}
+		}
+
+		start := time.Now()
+
+		c.Set(contextKeyEntry, l.WithField("request_id", RequestIDFromContext(c)))
+
+		var reqBody, respBody *cappedBuffer
+		if cfg.CaptureBodies {
+			reqBody = captureRequestBody(c, cfg.CaptureBodyLimit, captureContentTypes)
+
+			respBody = newCappedBuffer(cfg.CaptureBodyLimit)
+			c.Writer = &responseBodyWriter{ResponseWriter: c.Writer, body: respBody}
+		}
+
+		c.Next()
+
+		ts := time.Now()
+		if cfg.UTC {
+			ts = ts.UTC()
+		}
+		latency := ts.Sub(start)
+		statusCode := c.Writer.Status()
+
+		msg := formatter(LogFormatterParams{
+			Request:      c.Request,
+			TimeStamp:    ts,
+			StatusCode:   statusCode,
+			Latency:      latency,
+			ClientIP:     c.ClientIP(),
+			Method:       c.Request.Method,
+			Path:         path,
+			BodySize:     c.Writer.Size(),
+			ErrorMessage: c.Errors.String(),
+			TimeFormat:   cfg.TimeFormat,
+		})
+
+		entryLogger := accessLogger
+		if statusCode >= 400 {
+			entryLogger = errorLogger
+		}
+
+		fields := logrus.Fields{
+			"tag":        tag,
+			"status":     statusCode,
+			"method":     c.Request.Method,
+			"path":       path,
+			"latency_ms": float64(latency) / float64(time.Millisecond),
+			"client_ip":  c.ClientIP(),
+			"user_agent": c.Request.UserAgent(),
+			"bytes":      c.Writer.Size(),
+			"referer":    c.Request.Referer(),
+			"request_id": RequestIDFromContext(c),
+		}
+
+		if cfg.CaptureBodies {
+			fields["request_headers"] = redactedHeaders(c.Request.Header, cfg.RedactHeaders)
+			if reqBody != nil {
+				fields["request_body"] = string(redactBody(reqBody.buf.Bytes(), c.Request.Header.Get("Content-Type"), cfg.RedactJSONFields))
+				if reqBody.truncated {
+					fields["request_body_truncated"] = true
+				}
+			}
+			if respBody != nil && contentTypeAllowed(c.Writer.Header().Get("Content-Type"), captureContentTypes) {
+				fields["response_body"] = string(redactBody(respBody.buf.Bytes(), c.Writer.Header().Get("Content-Type"), cfg.RedactJSONFields))
+				if respBody.truncated {
+					fields["response_body_truncated"] = true
+				}
+			}
+		}
+
+		entry := entryLogger.WithFields(fields).WithTime(ts)
+
+		switch {
+		case statusCode >= 400 && statusCode <= 499:
+			entry.Warning(msg)
+		case statusCode >= 500:
+			entry.Error(msg)
+		default:
+			entry.Debug(msg)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package ginlogrus
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+
+	"gopkg.in/gin-gonic/gin.v1"
+)
+
+func TestLoggerWithConfigSkipPaths(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var out bytes.Buffer
+	l := logrus.New()
+	l.Out = &out
+	l.Level = logrus.DebugLevel
+
+	r := gin.New()
+	r.Use(LoggerWithConfig(LoggerConfig{Logger: l, SkipPaths: []string{"/health"}}))
+	r.GET("/health", func(c *gin.Context) { c.Status(http.StatusOK) })
+	r.GET("/work", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/health", nil)
+	r.ServeHTTP(w, req)
+
+	if out.Len() != 0 {
+		t.Errorf("expected no log output for a skipped path, got: %s", out.String())
+	}
+
+	w2 := httptest.NewRecorder()
+	req2, _ := http.NewRequest("GET", "/work", nil)
+	r.ServeHTTP(w2, req2)
+
+	if out.Len() == 0 {
+		t.Error("expected a log line for a non-skipped path")
+	}
+}
+
+func TestLoggerWithConfigAccessErrorSplit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var access, errs bytes.Buffer
+	l := logrus.New()
+	l.Level = logrus.DebugLevel
+
+	r := gin.New()
+	r.Use(LoggerWithConfig(LoggerConfig{
+		Logger:       l,
+		AccessOutput: &access,
+		ErrorOutput:  &errs,
+	}))
+	r.GET("/ok", func(c *gin.Context) { c.Status(http.StatusOK) })
+	r.GET("/missing", func(c *gin.Context) { c.Status(http.StatusNotFound) })
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/ok", nil)
+	r.ServeHTTP(w, req)
+
+	if access.Len() == 0 {
+		t.Error("expected the 2xx request to be logged to AccessOutput")
+	}
+	if errs.Len() != 0 {
+		t.Errorf("expected nothing logged to ErrorOutput for a 2xx request, got: %s", errs.String())
+	}
+
+	access.Reset()
+	errs.Reset()
+
+	w2 := httptest.NewRecorder()
+	req2, _ := http.NewRequest("GET", "/missing", nil)
+	r.ServeHTTP(w2, req2)
+
+	if errs.Len() == 0 {
+		t.Error("expected the 404 request to be logged to ErrorOutput")
+	}
+	if access.Len() != 0 {
+		t.Errorf("expected nothing logged to AccessOutput for a 404 request, got: %s", access.String())
+	}
+}
+
+func TestLoggerWithConfigCustomFormatter(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var out bytes.Buffer
+	l := logrus.New()
+	l.Out = &out
+	l.Level = logrus.DebugLevel
+
+	called := false
+	r := gin.New()
+	r.Use(LoggerWithConfig(LoggerConfig{
+		Logger: l,
+		Formatter: func(p LogFormatterParams) string {
+			called = true
+			return "custom-message:" + p.Path
+		},
+	}))
+	r.GET("/custom", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/custom", nil)
+	r.ServeHTTP(w, req)
+
+	if !called {
+		t.Fatal("expected the custom Formatter to be invoked")
+	}
+	if !strings.Contains(out.String(), "custom-message:/custom") {
+		t.Errorf("expected log output to contain the custom message, got: %s", out.String())
+	}
+}
+
+func TestLoggerWithConfigZeroLevelLeavesLoggerLevelUntouched(t *testing.T) {
+	l := logrus.New()
+	l.Level = logrus.InfoLevel
+
+	LoggerWithConfig(LoggerConfig{Logger: l})
+
+	if l.Level != logrus.InfoLevel {
+		t.Errorf("Level = %v, want %v (zero-value cfg.Level must not overwrite it)", l.Level, logrus.InfoLevel)
+	}
+}
@@ -0,0 +1,81 @@
+package ginlogrus
+
+import (
+	"crypto/rand"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"gopkg.in/gin-gonic/gin.v1"
+)
+
+// HeaderRequestID is the header used to propagate the request ID to and
+// from clients. An inbound value is honored as-is; otherwise one is
+// generated.
+const HeaderRequestID = "X-Request-ID"
+
+const contextKeyRequestID = "ginlogrus.request_id"
+const contextKeyEntry = "ginlogrus.entry"
+
+// RequestID returns a gin.HandlerFunc that ensures every request carries
+// an X-Request-ID header, generating one when the client didn't send it,
+// and stashes it on the gin.Context so downstream middleware (including
+// Logger) and handlers can attach it to their log output.
+//
+// Example:
+//        router := gin.New()
+//        router.Use(ginlogrus.RequestID())
+//        router.Use(ginlogrus.Logger(l, "MYAPI", true, false, os.Stdout, logrus.InfoLevel))
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.Request.Header.Get(HeaderRequestID)
+		if id == "" {
+			id = newRequestID()
+		}
+
+		c.Set(contextKeyRequestID, id)
+		c.Writer.Header().Set(HeaderRequestID, id)
+		c.Next()
+	}
+}
+
+// RequestIDFromContext returns the request ID stashed by RequestID, or an
+// empty string if RequestID wasn't used.
+func RequestIDFromContext(c *gin.Context) string {
+	id, _ := c.Get(contextKeyRequestID)
+	s, _ := id.(string)
+	return s
+}
+
+// FromContext returns the *logrus.Entry that Logger pre-populated with the
+// request's correlation fields, so handlers can log with the same
+// request_id. It returns a bare entry from logrus.StandardLogger() if
+// Logger hasn't run yet.
+func FromContext(c *gin.Context) *logrus.Entry {
+	if e, ok := c.Get(contextKeyEntry); ok {
+		if entry, ok := e.(*logrus.Entry); ok {
+			return entry
+		}
+	}
+	return logrus.NewEntry(logrus.StandardLogger())
+}
+
+// fallbackRequestIDSeq is only touched when crypto/rand is unavailable,
+// to keep fallback IDs from colliding with each other within the same
+// timestamp.
+var fallbackRequestIDSeq uint64
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err == nil {
+		return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+	}
+
+	// crypto/rand.Read failed (e.g. a restricted container with no
+	// entropy source available) - fall back to a value that still
+	// varies per call instead of emitting the same all-zero ID forever.
+	seq := atomic.AddUint64(&fallbackRequestIDSeq, 1)
+	return fmt.Sprintf("fallback-%x-%x", time.Now().UnixNano(), seq)
+}
@@ -0,0 +1,90 @@
+package ginlogrus
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"gopkg.in/gin-gonic/gin.v1"
+)
+
+func TestNewRequestIDIsUnique(t *testing.T) {
+	seen := make(map[string]struct{})
+	for i := 0; i < 100; i++ {
+		id := newRequestID()
+		if id == "" {
+			t.Fatal("newRequestID returned an empty string")
+		}
+		if _, ok := seen[id]; ok {
+			t.Fatalf("newRequestID returned a duplicate: %s", id)
+		}
+		seen[id] = struct{}{}
+	}
+}
+
+func TestRequestIDGeneratesWhenMissing(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(RequestID())
+	r.GET("/x", func(c *gin.Context) {
+		c.String(http.StatusOK, RequestIDFromContext(c))
+	})
+
+	w1 := httptest.NewRecorder()
+	req1, _ := http.NewRequest("GET", "/x", nil)
+	r.ServeHTTP(w1, req1)
+
+	w2 := httptest.NewRecorder()
+	req2, _ := http.NewRequest("GET", "/x", nil)
+	r.ServeHTTP(w2, req2)
+
+	id1 := w1.Body.String()
+	id2 := w2.Body.String()
+
+	if id1 == "" || id2 == "" {
+		t.Fatalf("expected non-empty request IDs, got %q and %q", id1, id2)
+	}
+	if id1 == id2 {
+		t.Errorf("expected distinct request IDs across requests, got %q twice", id1)
+	}
+	if got := w1.Header().Get(HeaderRequestID); got != id1 {
+		t.Errorf("X-Request-ID response header = %q, want %q", got, id1)
+	}
+}
+
+func TestRequestIDHonorsInboundHeader(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(RequestID())
+	r.GET("/x", func(c *gin.Context) {
+		c.String(http.StatusOK, RequestIDFromContext(c))
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/x", nil)
+	req.Header.Set(HeaderRequestID, "inbound-id-123")
+	r.ServeHTTP(w, req)
+
+	if got := w.Body.String(); got != "inbound-id-123" {
+		t.Errorf("request ID = %q, want inbound value %q", got, "inbound-id-123")
+	}
+	if got := w.Header().Get(HeaderRequestID); got != "inbound-id-123" {
+		t.Errorf("X-Request-ID response header = %q, want %q", got, "inbound-id-123")
+	}
+}
+
+func TestFromContextWithoutLoggerReturnsUsableEntry(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/x", func(c *gin.Context) {
+		entry := FromContext(c)
+		if entry == nil {
+			t.Error("FromContext returned nil")
+		}
+		c.Status(http.StatusOK)
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/x", nil)
+	r.ServeHTTP(w, req)
+}
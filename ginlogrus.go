@@ -10,17 +10,17 @@
 //    package main
 //    import (
 //        "flag"
-//        "time"
+//        "os"
 //        log "github.com/sirupsen/logrus"
 //        "github.com/rocksolidlabs/gin-logrus"
 //        "github.com/gin-gonic/gin"
 //    )
 //    func main() {
 //        flag.Parse()
+//        l := log.New()
 //        router := gin.New()
-//        router.Use(ginlogrus. Logger("MYAPI", false, true, os.Stdout, logrus.WarnLevel))
-//        //..
-//        router.Use(gin.Recovery())
+//        router.Use(ginlogrus.Logger(l, "MYAPI", false, true, os.Stdout, log.WarnLevel))
+//        router.Use(ginlogrus.Recovery(l, true))
 //        log.Info("bootstrapped application")
 //        router.Run(":8080")
 //    }
@@ -29,26 +29,12 @@ package ginlogrus
 
 import (
 	"io"
-	"time"
 
 	"github.com/sirupsen/logrus"
 
 	"gopkg.in/gin-gonic/gin.v1"
 )
 
-var log *logrus.Logger
-
-var (
-	green   = string([]byte{27, 91, 57, 55, 59, 52, 50, 109})
-	white   = string([]byte{27, 91, 57, 48, 59, 52, 55, 109})
-	yellow  = string([]byte{27, 91, 57, 55, 59, 52, 51, 109})
-	red     = string([]byte{27, 91, 57, 55, 59, 52, 49, 109})
-	blue    = string([]byte{27, 91, 57, 55, 59, 52, 52, 109})
-	magenta = string([]byte{27, 91, 57, 55, 59, 52, 53, 109})
-	cyan    = string([]byte{27, 91, 57, 55, 59, 52, 54, 109})
-	reset   = string([]byte{27, 91, 48, 109})
-)
-
 // ErrorLogger returns an ErrorLoggerT with parameter gin.ErrorTypeAny
 func ErrorLogger() gin.HandlerFunc {
 	return ErrorLoggerT(gin.ErrorTypeAny)
@@ -73,124 +59,26 @@ func ErrorLoggerT(typ gin.ErrorType) gin.HandlerFunc {
 // process for a call. It formats the log entries similar to
 // http://godoc.org/github.com/gin-gonic/gin#Logger does.
 //
+// It configures only the *logrus.Logger instance passed in as l, so it is
+// safe to use several Loggers side by side (e.g. one per router group)
+// without them stomping on each other's formatter, output or level. For
+// more control (skip paths, a custom Formatter, ...) use LoggerWithConfig
+// directly.
+//
 // Example:
 //        router := gin.New()
-//        router.Use(ginlogrus.Logger(false, true, os.Stdout, log.WarnLevel))
+//        router.Use(ginlogrus.Logger(l, "MYAPI", false, true, os.Stdout, logrus.WarnLevel))
 func Logger(l *logrus.Logger, outputTag string, outputJSON bool, outputColor bool, outputFile io.Writer, outLevel logrus.Level) gin.HandlerFunc {
-
-	// set the logger
-	log = l
-
-	// Set the output tag
-	if outputTag == "" {
-		outputTag = "GIN"
-	}
-
-	// Log as JSON instead of the default ASCII formatter.
 	if outputJSON {
-		logrus.SetFormatter(&logrus.JSONFormatter{})
-		reset = ""
+		l.Formatter = &logrus.JSONFormatter{}
+	} else {
+		l.Formatter = &logrus.TextFormatter{FullTimestamp: true, DisableColors: !outputColor}
 	}
+	l.Out = outputFile
+	l.Level = outLevel
 
-	// Turn off logrus color
-	if !outputColor && !outputJSON {
-		logrus.SetFormatter(&logrus.TextFormatter{FullTimestamp: true, DisableColors: true})
-	}
-
-	// Output to stdout instead of the default stderr, could also be a file.
-	logrus.SetOutput(outputFile)
-
-	// Set log severity oputLevel or above.
-	logrus.SetLevel(outLevel)
-
-	return func(c *gin.Context) {
-		t := time.Now()
-
-		// process request
-		c.Next()
-
-		latency := time.Since(t)
-		clientIP := c.ClientIP()
-		method := c.Request.Method
-		statusCode := c.Writer.Status()
-		statusColor := reset
-		methodColor := reset
-		if outputColor {
-			statusColor = colorForStatus(statusCode)
-			methodColor = colorForMethod(method)
-		}
-		path := c.Request.URL.Path
-
-		switch {
-		case statusCode >= 400 && statusCode <= 499:
-			{
-				log.Warningf("[%s] |%s %3d %s| %12v | %s |%s  %s %-7s %s %s",
-					outputTag,
-					statusColor, statusCode, reset,
-					latency,
-					clientIP,
-					methodColor, reset, method,
-					path,
-					c.Errors.String(),
-				)
-			}
-		case statusCode >= 500:
-			{
-				log.Errorf("[%s] |%s %3d %s| %12v | %s |%s  %s %-7s %s %s",
-					outputTag,
-					statusColor, statusCode, reset,
-					latency,
-					clientIP,
-					methodColor, reset, method,
-					path,
-					c.Errors.String(),
-				)
-			}
-		default:
-			log.Debugf("[%s] |%s %3d %s| %12v | %s |%s  %s %-7s %s\n%s",
-				outputTag,
-				statusColor, statusCode, reset,
-				latency,
-				clientIP,
-				methodColor, reset, method,
-				path,
-				c.Errors.String(),
-			)
-		}
-
-	}
-}
-
-func colorForStatus(code int) string {
-	switch {
-	case code >= 200 && code <= 299:
-		return green
-	case code >= 300 && code <= 399:
-		return white
-	case code >= 400 && code <= 499:
-		return yellow
-	default:
-		return red
-	}
-}
-
-func colorForMethod(method string) string {
-	switch {
-	case method == "GET":
-		return blue
-	case method == "POST":
-		return cyan
-	case method == "PUT":
-		return yellow
-	case method == "DELETE":
-		return red
-	case method == "PATCH":
-		return green
-	case method == "HEAD":
-		return magenta
-	case method == "OPTIONS":
-		return white
-	default:
-		return reset
-	}
+	return LoggerWithConfig(LoggerConfig{
+		Logger: l,
+		Tag:    outputTag,
+	})
 }
@@ -0,0 +1,127 @@
+package ginlogrus
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"runtime/debug"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"gopkg.in/gin-gonic/gin.v1"
+)
+
+// Recovery returns a middleware that recovers from any panic raised
+// further down the chain and logs it through l as a single structured
+// entry carrying the same request_id as Logger, instead of gin's default
+// gin.Recovery(), which writes an unstructured stack trace straight to
+// stderr. Use it in place of gin.Recovery().
+//
+// When stack is true, the (filtered) panic stack trace is attached as the
+// "stack" field. Broken-pipe/connection-reset errors - the client going
+// away mid-response - are logged at WarnLevel without a stack, mirroring
+// the behavior gin's own recovery middleware added for the same case.
+func Recovery(l *logrus.Logger, stack bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			rec := recover()
+			if rec == nil {
+				return
+			}
+
+			entry := l.WithFields(logrus.Fields{
+				"panic":      fmt.Sprintf("%v", rec),
+				"request_id": RequestIDFromContext(c),
+				"method":     c.Request.Method,
+				"path":       c.Request.URL.Path,
+				"client_ip":  c.ClientIP(),
+			})
+
+			if isBrokenConnection(rec) {
+				entry.Warning("broken connection")
+				c.Error(fmt.Errorf("%v", rec)) // nolint: errcheck
+				c.Abort()
+				return
+			}
+
+			if stack {
+				entry = entry.WithField("stack", string(filterStack(debug.Stack())))
+			}
+			entry.Error("recovered from panic")
+
+			c.AbortWithStatus(http.StatusInternalServerError)
+		}()
+		c.Next()
+	}
+}
+
+// isBrokenConnection reports whether rec, the value recovered from a
+// panic, represents the client hanging up mid-write (EPIPE/ECONNRESET)
+// rather than an actual bug in the handler.
+func isBrokenConnection(rec interface{}) bool {
+	err, ok := rec.(error)
+	if !ok {
+		return false
+	}
+
+	opErr, ok := err.(*net.OpError)
+	if !ok {
+		return false
+	}
+
+	syscallErr, ok := opErr.Err.(*os.SyscallError)
+	if !ok {
+		return false
+	}
+
+	msg := strings.ToLower(syscallErr.Error())
+	return strings.Contains(msg, "broken pipe") || strings.Contains(msg, "connection reset by peer")
+}
+
+// filteredStackPackages lists the function-line substrings of frames that
+// get dropped from a captured stack trace: the Go runtime itself,
+// net/http's server internals, gin, and this package's own Recovery
+// frame, none of which help diagnose where the application panicked.
+var filteredStackPackages = []string{
+	"runtime.",
+	"runtime/debug.",
+	"net/http.",
+	"gopkg.in/gin-gonic/gin",
+	"github.com/gin-gonic/gin",
+	"github.com/rocksolidlabs/gin-logrus",
+	// The builtin panic trampoline frame is emitted by debug.Stack() as a
+	// bare "panic(...)" with no package prefix on every Go version.
+	"panic(",
+}
+
+// filterStack removes every (function, file:line) frame pair in stack
+// whose function belongs to filteredStackPackages, so the result contains
+// only application frames instead of a fixed-size, format-dependent
+// header trim.
+func filterStack(stack []byte) []byte {
+	lines := bytes.Split(bytes.TrimRight(stack, "\n"), []byte("\n"))
+	if len(lines) == 0 {
+		return stack
+	}
+
+	out := [][]byte{lines[0]} // "goroutine N [running]:" header
+	for i := 1; i+1 < len(lines); i += 2 {
+		fn, file := lines[i], lines[i+1]
+
+		skip := false
+		for _, pkg := range filteredStackPackages {
+			if bytes.Contains(fn, []byte(pkg)) {
+				skip = true
+				break
+			}
+		}
+		if skip {
+			continue
+		}
+		out = append(out, fn, file)
+	}
+	return bytes.Join(out, []byte("\n"))
+}
@@ -0,0 +1,102 @@
+package ginlogrus
+
+import (
+	"bytes"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"runtime/debug"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+
+	"gopkg.in/gin-gonic/gin.v1"
+)
+
+func TestFilterStackDropsRuntimeFrames(t *testing.T) {
+	captured := captureStackFromPanic(t)
+
+	filtered := filterStack(captured)
+
+	if bytes.Contains(filtered, []byte("panic(")) {
+		t.Errorf("filtered stack still contains a bare panic() frame:\n%s", filtered)
+	}
+	if bytes.Contains(filtered, []byte("runtime.gopanic")) {
+		t.Errorf("filtered stack still contains a runtime frame:\n%s", filtered)
+	}
+	// captureStackFromPanic itself lives in this package, which
+	// filterStack also strips (it's how the Recovery middleware's own
+	// deferred frame gets dropped in production); testing.tRunner is the
+	// nearest frame outside that exclusion list, so its survival shows
+	// the filter removes specific frames rather than truncating wholesale.
+	if !bytes.Contains(filtered, []byte("testing.tRunner")) {
+		t.Errorf("filtered stack dropped every caller frame:\n%s", filtered)
+	}
+}
+
+// captureStackFromPanic triggers a real panic/recover and returns the
+// resulting debug.Stack() output, so the test exercises the exact frame
+// shapes Go actually emits rather than a hand-built fixture.
+func captureStackFromPanic(t *testing.T) []byte {
+	t.Helper()
+
+	var stack []byte
+	func() {
+		defer func() {
+			if recover() != nil {
+				stack = debug.Stack()
+			}
+		}()
+		panic("boom")
+	}()
+	return stack
+}
+
+func TestRecoveryLogsPanicAndAborts(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var buf bytes.Buffer
+	l := logrus.New()
+	l.Out = &buf
+	l.Formatter = &logrus.JSONFormatter{}
+
+	r := gin.New()
+	r.Use(Recovery(l, true))
+	r.GET("/boom", func(c *gin.Context) {
+		panic("kaboom")
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/boom", nil)
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusInternalServerError)
+	}
+	if !strings.Contains(buf.String(), "kaboom") {
+		t.Errorf("log output missing panic value: %s", buf.String())
+	}
+	if !strings.Contains(buf.String(), `"stack"`) {
+		t.Errorf("log output missing stack field: %s", buf.String())
+	}
+}
+
+func TestIsBrokenConnection(t *testing.T) {
+	brokenPipe := &net.OpError{Op: "write", Err: &os.SyscallError{Syscall: "write", Err: syscallErrBrokenPipe{}}}
+	if !isBrokenConnection(brokenPipe) {
+		t.Error("expected broken pipe net.OpError to be detected")
+	}
+
+	if isBrokenConnection("not an error") {
+		t.Error("expected a non-error panic value not to be detected as a broken connection")
+	}
+	if isBrokenConnection(os.ErrNotExist) {
+		t.Error("expected an unrelated error not to be detected as a broken connection")
+	}
+}
+
+type syscallErrBrokenPipe struct{}
+
+func (syscallErrBrokenPipe) Error() string { return "broken pipe" }
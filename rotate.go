@@ -0,0 +1,72 @@
+package ginlogrus
+
+import (
+	"io"
+	"time"
+
+	rotatelogs "github.com/lestrrat-go/file-rotatelogs"
+)
+
+// RotateOptions configures the log rotation performed by RotatingWriter. A
+// zero value for any field leaves the corresponding
+// github.com/lestrrat-go/file-rotatelogs default in place.
+type RotateOptions struct {
+	// MaxAge is how long to keep old log files around before they are
+	// removed.
+	MaxAge time.Duration
+
+	// RotationTime is how often a new log file is started.
+	RotationTime time.Duration
+
+	// RotationSize rotates the current file once it exceeds this many
+	// bytes, independent of RotationTime.
+	RotationSize int64
+
+	// LinkName, when set, is kept as a symlink pointing at the current
+	// log file.
+	LinkName string
+
+	// Location is the timezone used to expand the strftime pattern in
+	// path. Defaults to time.Local.
+	Location *time.Location
+}
+
+// RotatingWriter returns an io.Writer that rotates the file at path
+// according to opts, on top of github.com/lestrrat-go/file-rotatelogs.
+// path may contain strftime patterns (e.g. "access.%Y%m%d.log").
+//
+// Example:
+//        w, err := ginlogrus.RotatingWriter("/var/log/myapi/access.%Y%m%d.log", ginlogrus.RotateOptions{
+//                MaxAge:       7 * 24 * time.Hour,
+//                RotationTime: 24 * time.Hour,
+//                LinkName:     "/var/log/myapi/access.log",
+//        })
+func RotatingWriter(path string, opts RotateOptions) (io.Writer, error) {
+	rotateOpts := make([]rotatelogs.Option, 0, 5)
+
+	if opts.MaxAge > 0 {
+		rotateOpts = append(rotateOpts, rotatelogs.WithMaxAge(opts.MaxAge))
+	}
+	if opts.RotationTime > 0 {
+		rotateOpts = append(rotateOpts, rotatelogs.WithRotationTime(opts.RotationTime))
+	}
+	if opts.RotationSize > 0 {
+		rotateOpts = append(rotateOpts, rotatelogs.WithRotationSize(opts.RotationSize))
+	}
+	if opts.LinkName != "" {
+		rotateOpts = append(rotateOpts, rotatelogs.WithLinkName(opts.LinkName))
+	}
+	if opts.Location != nil {
+		rotateOpts = append(rotateOpts, rotatelogs.WithLocation(opts.Location))
+	}
+
+	return rotatelogs.New(path, rotateOpts...)
+}
+
+// MultiOutput returns an io.Writer that duplicates every write to all of
+// writers, e.g. to fan the same access log out to stdout and a rotating
+// file at the same time. It is a thin wrapper around io.MultiWriter kept
+// here so callers don't need to reach for the io package just for this.
+func MultiOutput(writers ...io.Writer) io.Writer {
+	return io.MultiWriter(writers...)
+}
@@ -0,0 +1,35 @@
+package ginlogrus
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestMultiOutputFansOutToAllWriters(t *testing.T) {
+	var a, b bytes.Buffer
+
+	w := MultiOutput(&a, &b)
+
+	n, err := w.Write([]byte("hello"))
+	if err != nil {
+		t.Fatalf("Write returned error: %v", err)
+	}
+	if n != len("hello") {
+		t.Fatalf("Write returned n=%d, want %d", n, len("hello"))
+	}
+
+	if a.String() != "hello" {
+		t.Errorf("writer a = %q, want %q", a.String(), "hello")
+	}
+	if b.String() != "hello" {
+		t.Errorf("writer b = %q, want %q", b.String(), "hello")
+	}
+}
+
+func TestMultiOutputNoWriters(t *testing.T) {
+	w := MultiOutput()
+
+	if _, err := w.Write([]byte("anything")); err != nil {
+		t.Fatalf("Write with no writers returned error: %v", err)
+	}
+}